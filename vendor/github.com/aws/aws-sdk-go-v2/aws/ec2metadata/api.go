@@ -0,0 +1,63 @@
+package ec2metadata
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ServiceName is the name of the service.
+const ServiceName = "ec2metadata"
+
+// defaultEndpoint is the endpoint of the EC2 instance metadata service.
+const defaultEndpoint = "http://169.254.169.254/latest"
+
+// A Client is an EC2 Metadata service Client.
+type Client struct {
+	*aws.Client
+
+	// EnableIMDSv1Fallback controls whether the client is allowed to fall
+	// back to unauthenticated IMDSv1 requests when the IMDSv2 token
+	// endpoint is unreachable or returns a 404. It defaults to true so
+	// existing environments keep working; set it to false to force
+	// strict IMDSv2-only behavior.
+	EnableIMDSv1Fallback bool
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExp    time.Time
+	v1OnlyUntil time.Time
+
+	cache Cache
+}
+
+// New creates a new instance of the EC2 Metadata client from cfg.
+// This client is safe to use across multiple goroutines.
+func New(cfg aws.Config) *Client {
+	return NewClient(cfg, defaultEndpoint)
+}
+
+// NewClient returns a new EC2 Metadata client pointed at the given endpoint.
+func NewClient(cfg aws.Config, endpoint string) *Client {
+	if len(endpoint) == 0 {
+		endpoint = defaultEndpoint
+	}
+
+	svc := &Client{
+		Client:               aws.NewClient(cfg, ServiceName, endpoint),
+		EnableIMDSv1Fallback: true,
+		cache:                newDefaultCache(),
+	}
+
+	svc.Handlers.Sign.PushBack(svc.attachTokenHandler)
+
+	return svc
+}
+
+// metadataOutput is the shared response shape used by the metadata,
+// user-data, and dynamic-data operations; Content holds the raw response
+// body as a string.
+type metadataOutput struct {
+	Content string
+}
@@ -1,6 +1,7 @@
 package ec2metadata
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,6 +17,18 @@ import (
 // instance metdata service. The content will be returned as a string, or
 // error if the request failed.
 func (c *Client) GetMetadata(p string) (string, error) {
+	return c.GetMetadataWithContext(context.Background(), p)
+}
+
+// GetMetadataWithContext is the context-aware variant of GetMetadata. The
+// provided ctx bounds how long the underlying HTTP call is allowed to run,
+// which callers on a startup deadline (node bring-up, agent init) can use
+// to avoid blocking indefinitely.
+func (c *Client) GetMetadataWithContext(ctx aws.Context, p string) (string, error) {
+	if cached, ok := c.cache.Get(p); ok {
+		return cached, nil
+	}
+
 	op := &aws.Operation{
 		Name:       "GetMetadata",
 		HTTPMethod: "GET",
@@ -24,14 +37,33 @@ func (c *Client) GetMetadata(p string) (string, error) {
 
 	output := &metadataOutput{}
 	req := c.NewRequest(op, nil, output)
+	req.SetContext(ctx)
 
-	return output.Content, req.Send()
+	if err := req.Send(); err != nil {
+		return "", err
+	}
+
+	c.cache.Set(p, output.Content, cacheTTL(p))
+	return output.Content, nil
 }
 
 // GetUserData returns the userdata that was configured for the service. If
 // there is no user-data setup for the EC2 instance a "NotFoundError" error
 // code will be returned.
 func (c *Client) GetUserData() (string, error) {
+	return c.GetUserDataWithContext(context.Background())
+}
+
+// userDataCacheKey namespaces the user-data cache entry away from the
+// meta-data/dynamic-data path spaces.
+const userDataCacheKey = "user-data"
+
+// GetUserDataWithContext is the context-aware variant of GetUserData.
+func (c *Client) GetUserDataWithContext(ctx aws.Context) (string, error) {
+	if cached, ok := c.cache.Get(userDataCacheKey); ok {
+		return cached, nil
+	}
+
 	op := &aws.Operation{
 		Name:       "GetUserData",
 		HTTPMethod: "GET",
@@ -40,19 +72,35 @@ func (c *Client) GetUserData() (string, error) {
 
 	output := &metadataOutput{}
 	req := c.NewRequest(op, nil, output)
+	req.SetContext(ctx)
 	req.Handlers.UnmarshalError.PushBack(func(r *aws.Request) {
 		if r.HTTPResponse.StatusCode == http.StatusNotFound {
 			r.Error = awserr.New("NotFoundError", "user-data not found", r.Error)
 		}
 	})
 
-	return output.Content, req.Send()
+	if err := req.Send(); err != nil {
+		return "", err
+	}
+
+	c.cache.Set(userDataCacheKey, output.Content, cacheTTL(userDataCacheKey))
+	return output.Content, nil
 }
 
 // GetDynamicData uses the path provided to request information from the EC2
 // instance metadata service for dynamic data. The content will be returned
 // as a string, or error if the request failed.
 func (c *Client) GetDynamicData(p string) (string, error) {
+	return c.GetDynamicDataWithContext(context.Background(), p)
+}
+
+// GetDynamicDataWithContext is the context-aware variant of GetDynamicData.
+func (c *Client) GetDynamicDataWithContext(ctx aws.Context, p string) (string, error) {
+	cacheKey := "dynamic:" + p
+	if cached, ok := c.cache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
 	op := &aws.Operation{
 		Name:       "GetDynamicData",
 		HTTPMethod: "GET",
@@ -61,15 +109,27 @@ func (c *Client) GetDynamicData(p string) (string, error) {
 
 	output := &metadataOutput{}
 	req := c.NewRequest(op, nil, output)
+	req.SetContext(ctx)
+
+	if err := req.Send(); err != nil {
+		return "", err
+	}
 
-	return output.Content, req.Send()
+	c.cache.Set(cacheKey, output.Content, cacheTTL(cacheKey))
+	return output.Content, nil
 }
 
 // GetInstanceIdentityDocument retrieves an identity document describing an
 // instance. Error is returned if the request fails or is unable to parse
 // the response.
 func (c *Client) GetInstanceIdentityDocument() (EC2InstanceIdentityDocument, error) {
-	resp, err := c.GetDynamicData("instance-identity/document")
+	return c.GetInstanceIdentityDocumentWithContext(context.Background())
+}
+
+// GetInstanceIdentityDocumentWithContext is the context-aware variant of
+// GetInstanceIdentityDocument.
+func (c *Client) GetInstanceIdentityDocumentWithContext(ctx aws.Context) (EC2InstanceIdentityDocument, error) {
+	resp, err := c.GetDynamicDataWithContext(ctx, "instance-identity/document")
 	if err != nil {
 		return EC2InstanceIdentityDocument{},
 			awserr.New("EC2MetadataRequestError",
@@ -88,7 +148,12 @@ func (c *Client) GetInstanceIdentityDocument() (EC2InstanceIdentityDocument, err
 
 // IAMInfo retrieves IAM info from the metadata API
 func (c *Client) IAMInfo() (EC2IAMInfo, error) {
-	resp, err := c.GetMetadata("iam/info")
+	return c.IAMInfoWithContext(context.Background())
+}
+
+// IAMInfoWithContext is the context-aware variant of IAMInfo.
+func (c *Client) IAMInfoWithContext(ctx aws.Context) (EC2IAMInfo, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "iam/info")
 	if err != nil {
 		return EC2IAMInfo{},
 			awserr.New("EC2MetadataRequestError",
@@ -113,7 +178,12 @@ func (c *Client) IAMInfo() (EC2IAMInfo, error) {
 
 // Region returns the region the instance is running in.
 func (c *Client) Region() (string, error) {
-	resp, err := c.GetMetadata("placement/availability-zone")
+	return c.RegionWithContext(context.Background())
+}
+
+// RegionWithContext is the context-aware variant of Region.
+func (c *Client) RegionWithContext(ctx aws.Context) (string, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "placement/availability-zone")
 	if err != nil {
 		return "", err
 	}
@@ -126,11 +196,38 @@ func (c *Client) Region() (string, error) {
 // service.  Can be used to determine if application is running within an EC2
 // Instance and the metadata service is available.
 func (c *Client) Available() bool {
-	if _, err := c.GetMetadata("instance-id"); err != nil {
-		return false
+	return c.AvailableWithContext(context.Background())
+}
+
+// availableCacheKey is deliberately distinct from the "instance-id" path
+// cache entry, which is cached for much longer: Available needs its own
+// short-lived result so that recovery from a temporarily-unreachable IMDS
+// is still detected promptly.
+const availableCacheKey = "__available__"
+
+// AvailableWithContext is the context-aware variant of Available.
+func (c *Client) AvailableWithContext(ctx aws.Context) bool {
+	if cached, ok := c.cache.Get(availableCacheKey); ok {
+		return cached == "true"
 	}
 
-	return true
+	op := &aws.Operation{
+		Name:       "GetMetadata",
+		HTTPMethod: "GET",
+		HTTPPath:   suffixPath("/meta-data", "instance-id"),
+	}
+
+	output := &metadataOutput{}
+	req := c.NewRequest(op, nil, output)
+	req.SetContext(ctx)
+
+	available := "true"
+	if err := req.Send(); err != nil {
+		available = "false"
+	}
+
+	c.cache.Set(availableCacheKey, available, availableCacheTTL)
+	return available == "true"
 }
 
 // An EC2IAMInfo provides the shape for unmarshaling
@@ -168,4 +265,4 @@ func suffixPath(base, add string) string {
 		reqPath += "/"
 	}
 	return reqPath
-}
\ No newline at end of file
+}
@@ -0,0 +1,113 @@
+package ec2metadata
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// shortCacheTTL is used for metadata that can change while an instance is
+// running, such as the attached network interfaces.
+const shortCacheTTL = 5 * time.Second
+
+// longCacheTTL is used for metadata that is fixed for the lifetime of an
+// instance, such as its instance ID or identity document.
+const longCacheTTL = 24 * time.Hour
+
+// availableCacheTTL is deliberately much shorter than longCacheTTL so that
+// Available() notices promptly once a temporarily-unreachable IMDS comes
+// back.
+const availableCacheTTL = 1 * time.Second
+
+// Cache is the extension point for caching metadata responses. Get reports
+// whether a non-expired value is cached for path; Set stores value for
+// path until ttl elapses. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(path string) (string, bool)
+	Set(path, value string, ttl time.Duration)
+}
+
+// invalidator is implemented by Cache implementations that support
+// removing entries by path prefix. InvalidateCache is a no-op against
+// caches that don't implement it.
+type invalidator interface {
+	Invalidate(prefix string)
+}
+
+// WithCache installs cache as the Client's metadata response cache,
+// replacing the default in-memory one. It returns the Client so it can be
+// chained off New/NewClient.
+func (c *Client) WithCache(cache Cache) *Client {
+	c.cache = cache
+	return c
+}
+
+// InvalidateCache drops any cached responses whose path starts with
+// prefix. Callers that observe ENI attach/detach events can use this to
+// force the next read of network/** metadata to hit the service again. It
+// has no effect if the installed Cache doesn't support invalidation.
+func (c *Client) InvalidateCache(prefix string) {
+	if inv, ok := c.cache.(invalidator); ok {
+		inv.Invalidate(prefix)
+	}
+}
+
+// cacheTTL picks the TTL to use for a given meta-data/dynamic-data path:
+// short for the network tree, which can change as ENIs are attached or
+// detached, long for the identifiers fixed at launch and for user-data,
+// short otherwise.
+func cacheTTL(path string) time.Duration {
+	switch {
+	case strings.HasPrefix(path, "network/"):
+		return shortCacheTTL
+	case path == "instance-id", path == "dynamic:instance-identity/document", path == userDataCacheKey:
+		return longCacheTTL
+	default:
+		return shortCacheTTL
+	}
+}
+
+type cacheEntry struct {
+	value  string
+	expiry time.Time
+}
+
+// defaultCache is the in-memory Cache installed on every Client unless
+// overridden with WithCache.
+type defaultCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newDefaultCache() *defaultCache {
+	return &defaultCache{entries: map[string]cacheEntry{}}
+}
+
+func (d *defaultCache) Get(path string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.entries[path]
+	if !ok || time.Now().After(e.expiry) {
+		return "", false
+	}
+	return e.value, true
+}
+
+func (d *defaultCache) Set(path, value string, ttl time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.entries[path] = cacheEntry{value: value, expiry: time.Now().Add(ttl)}
+}
+
+func (d *defaultCache) Invalidate(prefix string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k := range d.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(d.entries, k)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package ec2metadata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultCache_ExpiresEntries(t *testing.T) {
+	c := newDefaultCache()
+	c.Set("instance-id", "i-1234567890abcdef0", time.Millisecond)
+
+	if _, ok := c.Get("instance-id"); !ok {
+		t.Fatal("expected freshly set entry to be present")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("instance-id"); ok {
+		t.Error("expected entry to have expired")
+	}
+}
+
+func TestDefaultCache_InvalidateByPrefix(t *testing.T) {
+	c := newDefaultCache()
+	c.Set("network/interfaces/macs", "0e:1a:2b:3c:4d:5e/", time.Hour)
+	c.Set("instance-id", "i-1234567890abcdef0", time.Hour)
+
+	c.Invalidate("network/")
+
+	if _, ok := c.Get("network/interfaces/macs"); ok {
+		t.Error("expected network/ entry to be invalidated")
+	}
+	if _, ok := c.Get("instance-id"); !ok {
+		t.Error("expected unrelated entry to survive invalidation")
+	}
+}
@@ -0,0 +1,77 @@
+package ec2metadata_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ec2metadata"
+)
+
+func TestGetMetadata_CachesAcrossCalls(t *testing.T) {
+	var instanceIDCalls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			w.Write([]byte("token"))
+		case r.URL.Path == "/meta-data/instance-id":
+			instanceIDCalls++
+			w.Write([]byte("i-1234567890abcdef0"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := ec2metadata.NewClient(aws.Config{}, server.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetMetadata("instance-id"); err != nil {
+			t.Fatalf("GetMetadata returned error: %v", err)
+		}
+	}
+
+	if instanceIDCalls != 1 {
+		t.Errorf("got %d requests to instance-id, want 1 (subsequent calls should be served from cache)", instanceIDCalls)
+	}
+}
+
+func TestInvalidateCache_ForcesRefresh(t *testing.T) {
+	var macsCalls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			w.Write([]byte("token"))
+		case r.URL.Path == "/meta-data/network/interfaces/macs":
+			macsCalls++
+			w.Write([]byte("0e:1a:2b:3c:4d:5e/\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := ec2metadata.NewClient(aws.Config{}, server.URL)
+
+	if _, err := client.GetMetadata("network/interfaces/macs"); err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if _, err := client.GetMetadata("network/interfaces/macs"); err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if macsCalls != 1 {
+		t.Fatalf("got %d requests before invalidation, want 1", macsCalls)
+	}
+
+	client.InvalidateCache("network/")
+
+	if _, err := client.GetMetadata("network/interfaces/macs"); err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if macsCalls != 2 {
+		t.Errorf("got %d requests after invalidation, want 2 (cache entry should have been dropped)", macsCalls)
+	}
+}
@@ -0,0 +1,79 @@
+package ec2metadata
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+)
+
+// EC2RoleCredentials mirrors the JSON document returned by
+// iam/security-credentials/<role>.
+type EC2RoleCredentials struct {
+	Code            string
+	LastUpdated     time.Time
+	Type            string
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+// GetRoleName returns the name of the IAM role attached to the instance's
+// default instance profile.
+func (c *Client) GetRoleName() (string, error) {
+	return c.GetRoleNameWithContext(context.Background())
+}
+
+// GetRoleNameWithContext is the context-aware variant of GetRoleName.
+func (c *Client) GetRoleNameWithContext(ctx aws.Context) (string, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "iam/security-credentials/")
+	if err != nil {
+		return "", awserr.New("EC2MetadataRequestError",
+			"failed to get IAM role name", err)
+	}
+
+	role := strings.TrimSpace(strings.SplitN(resp, "\n", 2)[0])
+	if role == "" {
+		return "", awserr.New("EC2MetadataError", "no IAM role attached to this instance", nil)
+	}
+
+	return role, nil
+}
+
+// GetRoleCredentials retrieves the temporary credentials vended for role
+// from the IAM security-credentials tree.
+func (c *Client) GetRoleCredentials(role string) (EC2RoleCredentials, error) {
+	return c.GetRoleCredentialsWithContext(context.Background(), role)
+}
+
+// GetRoleCredentialsWithContext is the context-aware variant of
+// GetRoleCredentials. ctx is passed through to the underlying metadata
+// fetch, so a credential provider built on top of this client can bound
+// the fetch with its caller's deadline.
+func (c *Client) GetRoleCredentialsWithContext(ctx aws.Context, role string) (EC2RoleCredentials, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "iam/security-credentials/"+role)
+	if err != nil {
+		return EC2RoleCredentials{},
+			awserr.New("EC2MetadataRequestError",
+				"failed to get IAM role credentials", err)
+	}
+
+	creds := EC2RoleCredentials{}
+	if err := json.NewDecoder(strings.NewReader(resp)).Decode(&creds); err != nil {
+		return EC2RoleCredentials{},
+			awserr.New("SerializationError",
+				"failed to decode IAM role credentials", err)
+	}
+
+	if creds.Code != "Success" {
+		return EC2RoleCredentials{},
+			awserr.New("EC2MetadataError",
+				"failed to get IAM role credentials ("+creds.Code+")", nil)
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,173 @@
+package ec2metadata
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+)
+
+const (
+	// tokenPath is the IMDSv2 session token endpoint.
+	tokenPath = "/api/token"
+
+	// tokenTTLHeader and tokenHeader are the request headers used to
+	// negotiate and present an IMDSv2 session token.
+	tokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+	tokenHeader    = "X-aws-ec2-metadata-token"
+
+	// defaultTokenTTL is used for tokens fetched implicitly by the
+	// attachTokenHandler.
+	defaultTokenTTL = 21600 * time.Second
+
+	// getTokenOperation names the token request so attachTokenHandler can
+	// recognize and skip it, the same swap-based recursion guard newer
+	// AWS SDKs use.
+	getTokenOperation = "GetToken"
+
+	// v1OnlyRecheckInterval bounds how long attachTokenHandler will
+	// remember that the token endpoint is unreachable/404ing before
+	// trying it again, instead of paying a failed PUT /api/token on
+	// every single cache-miss call.
+	v1OnlyRecheckInterval = 1 * time.Minute
+)
+
+// getToken issues a PUT to the IMDSv2 token endpoint and caches the
+// returned token together with its expiration. It is safe to call
+// concurrently. ctx bounds the token request itself, so a caller's
+// deadline is honored even on the implicit fetch triggered by
+// attachTokenHandler.
+func (c *Client) getToken(ctx aws.Context, duration time.Duration) (string, error) {
+	op := &aws.Operation{
+		Name:       getTokenOperation,
+		HTTPMethod: "PUT",
+		HTTPPath:   tokenPath,
+	}
+
+	output := &metadataOutput{}
+	req := c.NewRequest(op, nil, output)
+	req.SetContext(ctx)
+	req.HTTPRequest.Header.Set(tokenTTLHeader, fmt.Sprintf("%d", int64(duration.Seconds())))
+
+	if err := req.Send(); err != nil {
+		return "", err
+	}
+
+	c.tokenMu.Lock()
+	c.token = output.Content
+	c.tokenExp = time.Now().Add(duration)
+	c.tokenMu.Unlock()
+
+	return output.Content, nil
+}
+
+// cachedToken returns the current token if it hasn't expired yet.
+func (c *Client) cachedToken() (string, bool) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.token == "" || time.Now().After(c.tokenExp) {
+		return "", false
+	}
+	return c.token, true
+}
+
+// invalidateToken clears the cached token, forcing the next request to
+// fetch a fresh one.
+func (c *Client) invalidateToken() {
+	c.tokenMu.Lock()
+	c.token = ""
+	c.tokenExp = time.Time{}
+	c.tokenMu.Unlock()
+}
+
+// rememberedV1Only reports whether the token endpoint was recently found
+// to be unreachable/404ing, so attachTokenHandler can skip straight to
+// unauthenticated IMDSv1 behavior instead of re-attempting the token PUT
+// on every call.
+func (c *Client) rememberedV1Only() bool {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	return !c.v1OnlyUntil.IsZero() && time.Now().Before(c.v1OnlyUntil)
+}
+
+// rememberV1Only records that the token endpoint is unavailable for
+// v1OnlyRecheckInterval.
+func (c *Client) rememberV1Only() {
+	c.tokenMu.Lock()
+	c.v1OnlyUntil = time.Now().Add(v1OnlyRecheckInterval)
+	c.tokenMu.Unlock()
+}
+
+// attachTokenHandler is installed on Handlers.Sign so that every
+// GetMetadata/GetUserData/GetDynamicData (and the helpers built on top of
+// them) request carries a valid IMDSv2 token. It fetches a token on first
+// use, retries once after invalidating the token on a 401/403, and falls
+// back to unauthenticated IMDSv1 behavior if the token endpoint 404s or is
+// unreachable and EnableIMDSv1Fallback is set. That v1-only outcome is
+// itself remembered for v1OnlyRecheckInterval, so a confirmed IMDSv1-only
+// instance doesn't pay a failed token PUT on every cache-miss call.
+func (c *Client) attachTokenHandler(r *aws.Request) {
+	if r.Operation.Name == getTokenOperation {
+		return
+	}
+
+	if c.rememberedV1Only() {
+		return
+	}
+
+	token, ok := c.cachedToken()
+	if !ok {
+		t, err := c.getToken(r.Context(), defaultTokenTTL)
+		if err != nil {
+			if !c.canFallBackToIMDSv1(err) {
+				r.Error = awserr.New("EC2MetadataRequestError",
+					"failed to get IMDSv2 token", err)
+				return
+			}
+			c.rememberV1Only()
+			return
+		}
+		token = t
+	}
+
+	r.HTTPRequest.Header.Set(tokenHeader, token)
+
+	r.Handlers.UnmarshalError.PushFront(func(rr *aws.Request) {
+		if rr.HTTPResponse == nil {
+			return
+		}
+		switch rr.HTTPResponse.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			// The token was rejected outright (as opposed to having simply
+			// expired); drop it and let the request's normal retry handling
+			// run attachTokenHandler again with a freshly fetched token.
+			// Only the first attempt is retried this way.
+			c.invalidateToken()
+			if rr.RetryCount == 0 {
+				rr.Retryable = aws.Bool(true)
+			}
+		}
+	})
+}
+
+// canFallBackToIMDSv1 reports whether the failure to obtain a token should
+// be treated as "this instance only speaks IMDSv1" rather than a hard
+// error: a 404 from the token endpoint, or any error that is not an HTTP
+// error response (such as a connection error reaching it).
+func (c *Client) canFallBackToIMDSv1(err error) bool {
+	if !c.EnableIMDSv1Fallback {
+		return false
+	}
+
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		return reqErr.StatusCode() == http.StatusNotFound
+	}
+
+	// Any other error fetching the token (e.g. connection refused/timeout)
+	// is treated as IMDSv1-only when fallback is enabled.
+	return true
+}
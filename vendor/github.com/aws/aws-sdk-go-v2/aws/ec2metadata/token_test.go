@@ -0,0 +1,121 @@
+package ec2metadata_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ec2metadata"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*ec2metadata.Client, func()) {
+	server := httptest.NewServer(handler)
+	client := ec2metadata.NewClient(aws.Config{}, server.URL)
+	return client, server.Close
+}
+
+func TestGetMetadata_AttachesIMDSv2Token(t *testing.T) {
+	const token = "test-token"
+
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			w.Write([]byte(token))
+		case r.Method == http.MethodGet && r.URL.Path == "/meta-data/instance-id":
+			if got := r.Header.Get("X-aws-ec2-metadata-token"); got != token {
+				http.Error(w, "missing token header", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("i-1234567890abcdef0"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+
+	got, err := client.GetMetadata("instance-id")
+	if err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if got != "i-1234567890abcdef0" {
+		t.Errorf("GetMetadata = %q, want instance id", got)
+	}
+}
+
+func TestGetMetadata_RetriesOnceAfterTokenRejected(t *testing.T) {
+	var tokensIssued, metadataCalls int
+
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			tokensIssued++
+			w.Write([]byte("token"))
+		case r.Method == http.MethodGet && r.URL.Path == "/meta-data/instance-id":
+			metadataCalls++
+			if metadataCalls == 1 {
+				// Simulate the first token having been rejected.
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("i-1234567890abcdef0"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+
+	got, err := client.GetMetadata("instance-id")
+	if err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if got != "i-1234567890abcdef0" {
+		t.Errorf("GetMetadata = %q, want instance id", got)
+	}
+	if tokensIssued < 2 {
+		t.Errorf("expected the rejected token to be invalidated and refetched, got %d token fetches", tokensIssued)
+	}
+}
+
+func TestGetMetadata_FallsBackToIMDSv1OnTokenNotFound(t *testing.T) {
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet && r.URL.Path == "/meta-data/instance-id":
+			if r.Header.Get("X-aws-ec2-metadata-token") != "" {
+				http.Error(w, "unexpected token on IMDSv1-only instance", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("i-1234567890abcdef0"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+
+	got, err := client.GetMetadata("instance-id")
+	if err != nil {
+		t.Fatalf("GetMetadata returned error: %v", err)
+	}
+	if got != "i-1234567890abcdef0" {
+		t.Errorf("GetMetadata = %q, want instance id", got)
+	}
+}
+
+func TestGetMetadata_StrictIMDSv2FailsWhenTokenUnavailable(t *testing.T) {
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/api/token" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("i-1234567890abcdef0"))
+	})
+	defer cleanup()
+
+	client.EnableIMDSv1Fallback = false
+
+	if _, err := client.GetMetadata("instance-id"); err == nil {
+		t.Fatal("expected GetMetadata to fail with EnableIMDSv1Fallback disabled and no token available")
+	}
+}
@@ -0,0 +1,237 @@
+package ec2metadata
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+)
+
+// A NetworkInterface describes one of the instance's attached ENIs, as
+// assembled by walking meta-data/network/interfaces/macs/<mac>/.
+type NetworkInterface struct {
+	MAC              string
+	InterfaceID      string
+	SubnetID         string
+	VpcID            string
+	SecurityGroupIDs []string
+	LocalIPv4s       []string
+	IPv6s            []string
+	SubnetIPv4CIDR   string
+	VpcIPv4CIDRs     []string
+}
+
+// An EC2InstancePlacement describes where an instance is running.
+type EC2InstancePlacement struct {
+	Region             string
+	AvailabilityZone   string
+	AvailabilityZoneID string
+	HostID             string
+}
+
+// An EC2SpotInstanceAction describes a pending Spot interruption, as
+// returned by meta-data/spot/instance-action.
+type EC2SpotInstanceAction struct {
+	Action string `json:"action"`
+	Time   string `json:"time"`
+}
+
+// GetNetworkInterfaces returns the set of ENIs attached to the instance by
+// walking the network/interfaces/macs/ metadata tree.
+func (c *Client) GetNetworkInterfaces() ([]NetworkInterface, error) {
+	return c.GetNetworkInterfacesWithContext(context.Background())
+}
+
+// GetNetworkInterfacesWithContext is the context-aware variant of
+// GetNetworkInterfaces.
+func (c *Client) GetNetworkInterfacesWithContext(ctx aws.Context) ([]NetworkInterface, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "network/interfaces/macs")
+	if err != nil {
+		return nil, awserr.New("EC2MetadataRequestError",
+			"failed to list network interface MACs", err)
+	}
+
+	var ifaces []NetworkInterface
+	for _, mac := range strings.Split(strings.TrimSpace(resp), "\n") {
+		mac = strings.TrimSuffix(mac, "/")
+		if mac == "" {
+			continue
+		}
+
+		iface, err := c.getNetworkInterface(ctx, mac)
+		if err != nil {
+			return nil, err
+		}
+		ifaces = append(ifaces, iface)
+	}
+
+	return ifaces, nil
+}
+
+func (c *Client) getNetworkInterface(ctx aws.Context, mac string) (NetworkInterface, error) {
+	base := "network/interfaces/macs/" + mac + "/"
+
+	iface := NetworkInterface{MAC: mac}
+
+	var err error
+	if iface.InterfaceID, err = c.getMetadataOrEmpty(ctx, base+"interface-id"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.SubnetID, err = c.getMetadataOrEmpty(ctx, base+"subnet-id"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.VpcID, err = c.getMetadataOrEmpty(ctx, base+"vpc-id"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.SubnetIPv4CIDR, err = c.getMetadataOrEmpty(ctx, base+"subnet-ipv4-cidr-block"); err != nil {
+		return NetworkInterface{}, err
+	}
+
+	if iface.SecurityGroupIDs, err = c.getMetadataList(ctx, base+"security-group-ids"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.LocalIPv4s, err = c.getMetadataList(ctx, base+"local-ipv4s"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.IPv6s, err = c.getMetadataList(ctx, base+"ipv6s"); err != nil {
+		return NetworkInterface{}, err
+	}
+	if iface.VpcIPv4CIDRs, err = c.getMetadataList(ctx, base+"vpc-ipv4-cidr-blocks"); err != nil {
+		return NetworkInterface{}, err
+	}
+
+	return iface, nil
+}
+
+// getMetadataOrEmpty returns "" instead of an error for paths the instance
+// doesn't have populated (a 404 from the metadata service).
+func (c *Client) getMetadataOrEmpty(ctx aws.Context, p string) (string, error) {
+	resp, err := c.GetMetadataWithContext(ctx, p)
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+		return "", awserr.New("EC2MetadataRequestError", "failed to get "+p, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) getMetadataList(ctx aws.Context, p string) ([]string, error) {
+	resp, err := c.getMetadataOrEmpty(ctx, p)
+	if err != nil || resp == "" {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSpace(resp), "\n"), nil
+}
+
+// GetInstanceTags returns the instance's tags as a map, from tags/instance/.
+// If instance-tag access hasn't been enabled for the instance, a
+// NotFoundError is returned.
+func (c *Client) GetInstanceTags() (map[string]string, error) {
+	return c.GetInstanceTagsWithContext(context.Background())
+}
+
+// GetInstanceTagsWithContext is the context-aware variant of
+// GetInstanceTags.
+func (c *Client) GetInstanceTagsWithContext(ctx aws.Context) (map[string]string, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "tags/instance")
+	if err != nil {
+		if isNotFound(err) {
+			return nil, awserr.New("NotFoundError",
+				"instance tags are not enabled for this instance", err)
+		}
+		return nil, awserr.New("EC2MetadataRequestError",
+			"failed to list instance tags", err)
+	}
+
+	tags := map[string]string{}
+	for _, key := range strings.Split(strings.TrimSpace(resp), "\n") {
+		if key == "" {
+			continue
+		}
+		value, err := c.GetMetadataWithContext(ctx, "tags/instance/"+key)
+		if err != nil {
+			return nil, awserr.New("EC2MetadataRequestError",
+				"failed to get instance tag "+key, err)
+		}
+		tags[key] = value
+	}
+
+	return tags, nil
+}
+
+// GetPlacement returns the instance's region, availability zone, AZ ID, and
+// host ID.
+func (c *Client) GetPlacement() (EC2InstancePlacement, error) {
+	return c.GetPlacementWithContext(context.Background())
+}
+
+// GetPlacementWithContext is the context-aware variant of GetPlacement.
+func (c *Client) GetPlacementWithContext(ctx aws.Context) (EC2InstancePlacement, error) {
+	az, err := c.getMetadataOrEmpty(ctx, "placement/availability-zone")
+	if err != nil {
+		return EC2InstancePlacement{}, err
+	}
+	azID, err := c.getMetadataOrEmpty(ctx, "placement/availability-zone-id")
+	if err != nil {
+		return EC2InstancePlacement{}, err
+	}
+	hostID, err := c.getMetadataOrEmpty(ctx, "placement/host-id")
+	if err != nil {
+		return EC2InstancePlacement{}, err
+	}
+
+	region := az
+	if len(region) > 0 {
+		region = region[:len(region)-1]
+	}
+
+	return EC2InstancePlacement{
+		Region:             region,
+		AvailabilityZone:   az,
+		AvailabilityZoneID: azID,
+		HostID:             hostID,
+	}, nil
+}
+
+// GetSpotInstanceAction returns the pending Spot interruption action and
+// time for the instance, for use in interruption handling. It returns a
+// NotFoundError if no interruption has been scheduled.
+func (c *Client) GetSpotInstanceAction() (EC2SpotInstanceAction, error) {
+	return c.GetSpotInstanceActionWithContext(context.Background())
+}
+
+// GetSpotInstanceActionWithContext is the context-aware variant of
+// GetSpotInstanceAction.
+func (c *Client) GetSpotInstanceActionWithContext(ctx aws.Context) (EC2SpotInstanceAction, error) {
+	resp, err := c.GetMetadataWithContext(ctx, "spot/instance-action")
+	if err != nil {
+		if isNotFound(err) {
+			return EC2SpotInstanceAction{}, awserr.New("NotFoundError",
+				"no Spot interruption action scheduled", err)
+		}
+		return EC2SpotInstanceAction{},
+			awserr.New("EC2MetadataRequestError",
+				"failed to get Spot instance action", err)
+	}
+
+	action := EC2SpotInstanceAction{}
+	if err := json.NewDecoder(strings.NewReader(resp)).Decode(&action); err != nil {
+		return EC2SpotInstanceAction{},
+			awserr.New("SerializationError",
+				"failed to decode Spot instance action", err)
+	}
+
+	return action, nil
+}
+
+// isNotFound reports whether err is a 404 from the metadata service.
+// Errors that wrap such a failure are not unwrapped.
+func isNotFound(err error) bool {
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		return reqErr.StatusCode() == 404
+	}
+	return false
+}
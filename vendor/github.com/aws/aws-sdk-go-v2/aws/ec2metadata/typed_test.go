@@ -0,0 +1,93 @@
+package ec2metadata_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ec2metadata"
+)
+
+func newTypedTestClient(t *testing.T, paths map[string]string) (*ec2metadata.Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/api/token" {
+			w.Write([]byte("token"))
+			return
+		}
+
+		resp, ok := paths[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(resp))
+	}))
+	client := ec2metadata.NewClient(aws.Config{}, server.URL)
+	return client, server.Close
+}
+
+func TestGetNetworkInterfaces(t *testing.T) {
+	client, cleanup := newTypedTestClient(t, map[string]string{
+		"/meta-data/network/interfaces/macs":                                          "0e:1a:2b:3c:4d:5e/\n",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/interface-id":           "eni-1234567890abcdef0",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/subnet-id":              "subnet-1234567890abcdef0",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/vpc-id":                 "vpc-1234567890abcdef0",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/subnet-ipv4-cidr-block": "10.0.0.0/24",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/security-group-ids":     "sg-1234567890abcdef0",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/local-ipv4s":            "10.0.0.5",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/ipv6s":                  "",
+		"/meta-data/network/interfaces/macs/0e:1a:2b:3c:4d:5e/vpc-ipv4-cidr-blocks":   "10.0.0.0/16",
+	})
+	defer cleanup()
+
+	ifaces, err := client.GetNetworkInterfaces()
+	if err != nil {
+		t.Fatalf("GetNetworkInterfaces returned error: %v", err)
+	}
+	if len(ifaces) != 1 {
+		t.Fatalf("got %d interfaces, want 1", len(ifaces))
+	}
+
+	iface := ifaces[0]
+	if iface.MAC != "0e:1a:2b:3c:4d:5e" || iface.InterfaceID != "eni-1234567890abcdef0" ||
+		iface.SubnetID != "subnet-1234567890abcdef0" || iface.VpcID != "vpc-1234567890abcdef0" {
+		t.Errorf("unexpected interface: %+v", iface)
+	}
+}
+
+func TestGetInstanceTags_NotEnabled(t *testing.T) {
+	client, cleanup := newTypedTestClient(t, map[string]string{})
+	defer cleanup()
+
+	if _, err := client.GetInstanceTags(); err == nil {
+		t.Fatal("expected NotFoundError when instance tags are disabled")
+	}
+}
+
+func TestGetPlacement(t *testing.T) {
+	client, cleanup := newTypedTestClient(t, map[string]string{
+		"/meta-data/placement/availability-zone":    "us-west-2a",
+		"/meta-data/placement/availability-zone-id": "usw2-az1",
+		"/meta-data/placement/host-id":              "h-1234567890abcdef0",
+	})
+	defer cleanup()
+
+	placement, err := client.GetPlacement()
+	if err != nil {
+		t.Fatalf("GetPlacement returned error: %v", err)
+	}
+	if placement.Region != "us-west-2" || placement.AvailabilityZone != "us-west-2a" ||
+		placement.AvailabilityZoneID != "usw2-az1" || placement.HostID != "h-1234567890abcdef0" {
+		t.Errorf("unexpected placement: %+v", placement)
+	}
+}
+
+func TestGetSpotInstanceAction_NotScheduled(t *testing.T) {
+	client, cleanup := newTypedTestClient(t, map[string]string{})
+	defer cleanup()
+
+	if _, err := client.GetSpotInstanceAction(); err == nil {
+		t.Fatal("expected NotFoundError when no interruption is scheduled")
+	}
+}